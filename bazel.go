@@ -0,0 +1,97 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dep
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/golang/dep/internal/gps"
+)
+
+// ExportBazel renders m and l into a deps.bzl fragment of go_repository
+// rules, one per project in l, so that Bazel/Gazelle builds can consume the
+// exact revisions dep solved without parsing Gopkg.toml/Gopkg.lock
+// themselves. Ovr entries in m override the rule's source and, if the
+// override is a branch, are noted in the rule's importpath comment; Ignored
+// projects are skipped entirely.
+func ExportBazel(m *Manifest, l *Lock) ([]byte, error) {
+	ignored := make(map[string]bool, len(m.Ignored))
+	for _, root := range m.Ignored {
+		ignored[root] = true
+	}
+
+	projects := make([]LockedProject, 0, len(l.Projects))
+	for _, lp := range l.Projects {
+		if ignored[string(lp.Root)] {
+			continue
+		}
+		projects = append(projects, lp)
+	}
+	sort.Slice(projects, func(i, j int) bool { return projects[i].Root < projects[j].Root })
+
+	var buf bytes.Buffer
+	buf.WriteString("# Code generated by dep ensure --bazel; DO NOT EDIT.\n\n")
+	buf.WriteString(`load("@bazel_gazelle//:deps.bzl", "go_repository")` + "\n\n")
+	buf.WriteString("def go_repositories():\n")
+
+	for _, lp := range projects {
+		source := lp.Source
+		var branch string
+		if ovr, ok := m.Ovr[lp.Root]; ok {
+			if ovr.Source != "" {
+				source = ovr.Source
+			}
+			if v, ok := ovr.Constraint.(gps.Version); ok && v.Type() == gps.IsBranch {
+				branch = v.String()
+			}
+		}
+
+		rev := string(lp.Revision)
+		if rev == "" {
+			return nil, fmt.Errorf("%s: no revision recorded in Gopkg.lock", lp.Root)
+		}
+
+		buf.WriteString("    go_repository(\n")
+		fmt.Fprintf(&buf, "        name = %q,\n", bazelRepoName(string(lp.Root)))
+		if branch != "" {
+			fmt.Fprintf(&buf, "        importpath = %q,  # overridden source tracks branch %q\n", lp.Root, branch)
+		} else {
+			fmt.Fprintf(&buf, "        importpath = %q,\n", lp.Root)
+		}
+		if source != "" && source != string(lp.Root) {
+			fmt.Fprintf(&buf, "        remote = %q,\n", source)
+			buf.WriteString("        vcs = \"git\",\n")
+		}
+		fmt.Fprintf(&buf, "        commit = %q,\n", rev)
+		if lp.Digest != "" {
+			fmt.Fprintf(&buf, "        sum = %q,\n", lp.Digest)
+		}
+		buf.WriteString("    )\n")
+	}
+
+	return buf.Bytes(), nil
+}
+
+var bazelNameDisallowed = regexp.MustCompile(`[^a-z0-9_]`)
+
+// bazelRepoName mangles a Go import path into the external workspace name
+// Gazelle would generate for it, e.g. "github.com/foo/bar" becomes
+// "com_github_foo_bar".
+func bazelRepoName(importPath string) string {
+	parts := strings.Split(importPath, "/")
+
+	host := strings.Split(parts[0], ".")
+	for i, j := 0, len(host)-1; i < j; i, j = i+1, j-1 {
+		host[i], host[j] = host[j], host[i]
+	}
+
+	all := append(host, parts[1:]...)
+	name := strings.ToLower(strings.Join(all, "_"))
+	return bazelNameDisallowed.ReplaceAllString(name, "_")
+}
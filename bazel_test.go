@@ -0,0 +1,74 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dep
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/golang/dep/internal/gps"
+	"github.com/golang/dep/internal/test"
+)
+
+func TestExportBazel(t *testing.T) {
+	h := test.NewHelper(t)
+	defer h.Cleanup()
+
+	golden := "bazel/golden.bzl"
+	want := h.GetTestFileString(golden)
+
+	m := &Manifest{
+		Ovr: map[gps.ProjectRoot]gps.ProjectProperties{
+			gps.ProjectRoot("github.com/golang/dep/internal/gps"): {
+				Source:     "https://github.com/golang/dep/internal/gps",
+				Constraint: gps.NewBranch("master"),
+			},
+		},
+	}
+	l := &Lock{
+		Projects: []LockedProject{
+			{
+				Root:     gps.ProjectRoot("github.com/babble/brook"),
+				Revision: gps.Revision("d05d5aca9f895d19e9265839bffeadd74a2d2ecb"),
+			},
+			{
+				Root:     gps.ProjectRoot("github.com/golang/dep/internal/gps"),
+				Revision: gps.Revision("7b08c2b5a9b9d89d0b8d36a6c6c6e5e3f5e5f5e5"),
+			},
+		},
+	}
+
+	got, err := ExportBazel(m, l)
+	if err != nil {
+		t.Fatalf("ExportBazel returned an error: %s", err)
+	}
+
+	if string(got) != want {
+		if *test.UpdateGolden {
+			if err := h.WriteTestFile(golden, string(got)); err != nil {
+				t.Fatal(err)
+			}
+		} else {
+			t.Errorf("Manifest/Lock did not export to Bazel as expected:\n\t(GOT): %s\n\t(WNT): %s", got, want)
+		}
+	}
+}
+
+func TestExportBazelIgnoresIgnoredProjects(t *testing.T) {
+	m := &Manifest{Ignored: []string{"github.com/foo/bar"}}
+	l := &Lock{
+		Projects: []LockedProject{
+			{Root: gps.ProjectRoot("github.com/foo/bar"), Revision: gps.Revision("deadbeef")},
+		},
+	}
+
+	got, err := ExportBazel(m, l)
+	if err != nil {
+		t.Fatalf("ExportBazel returned an error: %s", err)
+	}
+	if strings.Contains(string(got), "foo/bar") {
+		t.Errorf("expected ignored project to be omitted from Bazel export, got:\n%s", got)
+	}
+}
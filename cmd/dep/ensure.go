@@ -0,0 +1,68 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/golang/dep"
+	"github.com/pkg/errors"
+)
+
+const ensureShortHelp = `Ensure a dependency is safely vendored in the project`
+const ensureLongHelp = `
+Ensure gets a project into a complete, reproducible, and likely compilable
+state.
+`
+
+type ensureCommand struct {
+	bazel bool
+}
+
+func (cmd *ensureCommand) Name() string      { return "ensure" }
+func (cmd *ensureCommand) Args() string      { return "[-bazel] [packages]" }
+func (cmd *ensureCommand) ShortHelp() string { return ensureShortHelp }
+func (cmd *ensureCommand) LongHelp() string  { return ensureLongHelp }
+func (cmd *ensureCommand) Hidden() bool      { return false }
+
+func (cmd *ensureCommand) Register(fs *flag.FlagSet) {
+	fs.BoolVar(&cmd.bazel, "bazel", false, "also emit a deps.bzl with go_repository rules for the solved dependencies")
+}
+
+func (cmd *ensureCommand) Run(ctx *dep.Ctx, args []string) error {
+	p, err := ctx.LoadProject()
+	if err != nil {
+		return err
+	}
+
+	solution, err := ctx.Solve(p)
+	if err != nil {
+		return errors.Wrap(err, "failed to solve the project's dependencies")
+	}
+
+	if err := ctx.WriteLock(p, solution); err != nil {
+		return errors.Wrap(err, "failed to write Gopkg.lock")
+	}
+
+	if cmd.bazel {
+		if err := cmd.writeBazel(p, solution); err != nil {
+			return errors.Wrap(err, "failed to export Bazel deps.bzl")
+		}
+	}
+
+	return nil
+}
+
+// writeBazel renders the solved Manifest/Lock into deps.bzl in the project
+// root, opt-in output for Bazel/Gazelle-based builds.
+func (cmd *ensureCommand) writeBazel(p *dep.Project, l *dep.Lock) error {
+	out, err := dep.ExportBazel(p.Manifest, l)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(p.AbsRoot, "deps.bzl"), out, 0644)
+}
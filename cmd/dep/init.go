@@ -0,0 +1,111 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"flag"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/golang/dep"
+	"github.com/golang/dep/modconv"
+	"github.com/pkg/errors"
+)
+
+const initShortHelp = `Initialize a new project with a manifest and lock file`
+const initLongHelp = `
+Init writes a Gopkg.toml for the project at filepath root (the current
+directory by default). With -from-gomod it converts the project's go.mod (and
+go.sum, if present); with -i it asks about each discovered import. It writes
+no Gopkg.lock and vendors nothing, and without one of those flags it returns
+an error.
+`
+
+type initCommand struct {
+	fromGomod   bool
+	interactive bool
+}
+
+func (cmd *initCommand) Name() string      { return "init" }
+func (cmd *initCommand) Args() string      { return "[root]" }
+func (cmd *initCommand) ShortHelp() string { return initShortHelp }
+func (cmd *initCommand) LongHelp() string  { return initLongHelp }
+func (cmd *initCommand) Hidden() bool      { return false }
+
+func (cmd *initCommand) Register(fs *flag.FlagSet) {
+	fs.BoolVar(&cmd.fromGomod, "from-gomod", false, "generate Gopkg.toml from an existing go.mod/go.sum pair")
+	fs.BoolVar(&cmd.interactive, "i", false, "interactively choose a constraint for each discovered import")
+}
+
+func (cmd *initCommand) Run(ctx *dep.Ctx, args []string) error {
+	root := ctx.AbsRoot
+	if len(args) > 0 {
+		root = args[0]
+	}
+
+	if cmd.fromGomod {
+		return cmd.runFromGomod(root)
+	}
+
+	if cmd.interactive {
+		return cmd.runInteractive(ctx, root)
+	}
+
+	return errors.New("dep init without -from-gomod or -i is not implemented in this snapshot")
+}
+
+// runInteractive discovers the project's imports and walks the user through
+// the init wizard, then writes the resulting Manifest to Gopkg.toml.
+func (cmd *initCommand) runInteractive(ctx *dep.Ctx, root string) error {
+	importRoots, err := ctx.DiscoverImportRoots(root)
+	if err != nil {
+		return errors.Wrap(err, "could not discover imports")
+	}
+
+	m, err := runWizard(os.Stdout, os.Stdin, importRoots, func(r string) string {
+		return ctx.LatestTag(r)
+	})
+	if err != nil {
+		return errors.Wrap(err, "init wizard failed")
+	}
+
+	out, err := m.MarshalTOML()
+	if err != nil {
+		return errors.Wrap(err, "could not marshal Gopkg.toml")
+	}
+
+	return ioutil.WriteFile(filepath.Join(root, dep.ManifestName), out, 0644)
+}
+
+// runFromGomod converts the go.mod/go.sum pair at root into Gopkg.toml via
+// modconv.ImportGoMod. The Lock that ImportGoMod returns is not written; no
+// Gopkg.lock is produced.
+func (cmd *initCommand) runFromGomod(root string) error {
+	gomod, err := os.Open(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return errors.Wrap(err, "could not open go.mod")
+	}
+	defer gomod.Close()
+
+	var gosum io.Reader
+	if f, err := os.Open(filepath.Join(root, "go.sum")); err == nil {
+		defer f.Close()
+		gosum = f
+	}
+
+	m, _, err := modconv.ImportGoMod(gomod, gosum)
+	if err != nil {
+		return errors.Wrap(err, "could not import go.mod")
+	}
+
+	out, err := m.MarshalTOML()
+	if err != nil {
+		return errors.Wrap(err, "could not marshal Gopkg.toml")
+	}
+
+	return ioutil.WriteFile(filepath.Join(root, dep.ManifestName), out, 0644)
+}
@@ -0,0 +1,79 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Command dep is a prototype dependency management tool.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/golang/dep"
+)
+
+// command is a single dep subcommand, registered in commands below.
+type command interface {
+	Name() string
+	Args() string
+	ShortHelp() string
+	LongHelp() string
+	Hidden() bool
+	Register(fs *flag.FlagSet)
+	Run(ctx *dep.Ctx, args []string) error
+}
+
+var commands = []command{
+	&ensureCommand{},
+	&initCommand{},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	if len(args) == 0 {
+		usage()
+		return 1
+	}
+
+	for _, cmd := range commands {
+		if cmd.Name() != args[0] {
+			continue
+		}
+
+		fs := flag.NewFlagSet(cmd.Name(), flag.ExitOnError)
+		cmd.Register(fs)
+		fs.Parse(args[1:])
+
+		root, err := os.Getwd()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "dep: %s\n", err)
+			return 1
+		}
+		ctx := &dep.Ctx{AbsRoot: root}
+
+		if err := cmd.Run(ctx, fs.Args()); err != nil {
+			fmt.Fprintf(os.Stderr, "dep: %s\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	fmt.Fprintf(os.Stderr, "dep: unknown command %q\n", args[0])
+	usage()
+	return 1
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: dep <command> [arguments]")
+	fmt.Fprintln(os.Stderr, "\nCommands:")
+	for _, cmd := range commands {
+		if cmd.Hidden() {
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", cmd.Name(), cmd.ShortHelp())
+	}
+}
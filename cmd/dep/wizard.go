@@ -0,0 +1,241 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/golang/dep"
+	"github.com/golang/dep/internal/gps"
+	"github.com/pkg/errors"
+)
+
+// fieldSpec describes one question the init wizard asks about a discovered
+// project: its prompt text, a one-line help string, and the other fields
+// (by name) that must be answered first. Name is always a real TOML key
+// dep.ProjectFields or dep.ManifestFields recognizes.
+type fieldSpec struct {
+	Name      string
+	Prompt    string
+	Help      string
+	DependsOn []string
+}
+
+// wizardFieldOrder is the order the wizard walks the per-project keys it
+// asks about: source before the constraint-bearing keys. The suggested
+// version default depends only on the import root, not on the source
+// answer, and no branch default is suggested. name and metadata are
+// skipped - name is supplied by the caller, and metadata isn't collected
+// interactively.
+var wizardFieldOrder = []string{"source", "branch", "revision", "version"}
+
+// wizardFieldHelp gives the prompt and help text for each key wizardFields
+// may ask about, keyed by the same name dep.ProjectFields/dep.ManifestFields
+// use.
+var wizardFieldHelp = map[string]struct{ Prompt, Help string }{
+	"source": {
+		Prompt: "Use an alternate source (e.g. a fork)",
+		Help:   "Leave blank to use the import path itself as the source.",
+	},
+	"branch": {
+		Prompt: "Branch constraint",
+		Help:   `Track a named VCS branch, e.g. "master". Leave blank to use a revision or version instead.`,
+	},
+	"revision": {
+		Prompt: "Revision constraint",
+		Help:   "Pin to a full VCS commit hash. Leave blank to use a branch or version instead.",
+	},
+	"version": {
+		Prompt: "Version constraint",
+		Help:   `A semver range, e.g. ">=1.2.0, <2.0.0". Leave blank to use a branch or revision instead.`,
+	},
+	"ignored": {
+		Prompt: "Ignore this project (y/N)",
+		Help:   "Ignored projects are recorded in Gopkg.toml but never solved or vendored.",
+	},
+}
+
+// wizardFields drives the init wizard's questions from dep's own manifest
+// schema (dep.ProjectFields and dep.ManifestFields, the same metadata
+// validateManifest uses) rather than a hand-maintained list that could
+// drift from it.
+var wizardFields = buildWizardFields()
+
+func buildWizardFields() []fieldSpec {
+	var fields []fieldSpec
+	for _, name := range wizardFieldOrder {
+		if !dep.ProjectFields[name] {
+			continue
+		}
+		h := wizardFieldHelp[name]
+		fs := fieldSpec{Name: name, Prompt: h.Prompt, Help: h.Help}
+		if name != "source" {
+			fs.DependsOn = []string{"source"}
+		}
+		fields = append(fields, fs)
+	}
+	if dep.ManifestFields["ignored"] {
+		h := wizardFieldHelp["ignored"]
+		fields = append(fields, fieldSpec{Name: "ignored", Prompt: h.Prompt, Help: h.Help})
+	}
+	return fields
+}
+
+// orderedWizardFields topologically sorts wizardFields by DependsOn so that
+// a field is always asked after everything it depends on.
+func orderedWizardFields() ([]fieldSpec, error) {
+	byName := make(map[string]fieldSpec, len(wizardFields))
+	for _, f := range wizardFields {
+		byName[f.Name] = f
+	}
+
+	var ordered []fieldSpec
+	visited := make(map[string]bool, len(wizardFields))
+	visiting := make(map[string]bool, len(wizardFields))
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		if visited[name] {
+			return nil
+		}
+		if visiting[name] {
+			return fmt.Errorf("dependency cycle in wizard fields involving %q", name)
+		}
+		visiting[name] = true
+		for _, depName := range byName[name].DependsOn {
+			if err := visit(depName); err != nil {
+				return err
+			}
+		}
+		visiting[name] = false
+		visited[name] = true
+		ordered = append(ordered, byName[name])
+		return nil
+	}
+
+	for _, f := range wizardFields {
+		if err := visit(f.Name); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
+
+// wizardAnswers holds the raw responses gathered for one import root before
+// they're turned into a gps.ProjectProperties.
+type wizardAnswers struct {
+	source   string
+	branch   string
+	revision string
+	version  string
+	ignored  bool
+}
+
+// runWizard interactively asks, for each root in importRoots, a source
+// override, a branch/revision/version constraint, and whether to ignore it,
+// suggesting suggestedConstraint(root) as the version default. The result
+// is assembled into a Manifest ready for MarshalTOML.
+func runWizard(out io.Writer, in io.Reader, importRoots []string, suggestedConstraint func(root string) string) (*dep.Manifest, error) {
+	fields, err := orderedWizardFields()
+	if err != nil {
+		return nil, err
+	}
+
+	scanner := bufio.NewScanner(in)
+	m := &dep.Manifest{
+		Dependencies: make(map[gps.ProjectRoot]gps.ProjectProperties),
+		Ovr:          make(map[gps.ProjectRoot]gps.ProjectProperties),
+	}
+
+	for _, root := range importRoots {
+		fmt.Fprintf(out, "\n%s\n", root)
+
+		var ans wizardAnswers
+		for _, f := range fields {
+			def := ""
+			if f.Name == "version" {
+				def = suggestedConstraint(root)
+			}
+
+			fmt.Fprintf(out, "  %s [%s]\n  (help: %s)\n  > ", f.Prompt, def, f.Help)
+			if !scanner.Scan() {
+				err := scanner.Err()
+				if err == nil {
+					err = io.ErrUnexpectedEOF
+				}
+				return nil, errors.Wrap(err, "failed to read wizard input")
+			}
+			answer := strings.TrimSpace(scanner.Text())
+			if answer == "" {
+				answer = def
+			}
+
+			switch f.Name {
+			case "source":
+				ans.source = answer
+			case "branch":
+				ans.branch = answer
+			case "revision":
+				ans.revision = answer
+			case "version":
+				ans.version = answer
+			case "ignored":
+				ans.ignored = strings.EqualFold(answer, "y") || strings.EqualFold(answer, "yes")
+			}
+		}
+
+		if ans.ignored {
+			m.Ignored = append(m.Ignored, root)
+			continue
+		}
+
+		pp, err := wizardProjectProperties(ans)
+		if err != nil {
+			return nil, errors.Wrapf(err, "%s", root)
+		}
+		m.Dependencies[gps.ProjectRoot(root)] = pp
+	}
+
+	return m, nil
+}
+
+// wizardProjectProperties turns one root's answers into a
+// gps.ProjectProperties, rejecting answers that set more than one of
+// branch, revision, or version.
+func wizardProjectProperties(ans wizardAnswers) (gps.ProjectProperties, error) {
+	pp := gps.ProjectProperties{Source: ans.source}
+
+	set := 0
+	if ans.branch != "" {
+		set++
+	}
+	if ans.revision != "" {
+		set++
+	}
+	if ans.version != "" {
+		set++
+	}
+	if set > 1 {
+		return pp, errors.New("multiple constraints (branch, revision, or version) specified")
+	}
+
+	switch {
+	case ans.branch != "":
+		pp.Constraint = gps.NewBranch(ans.branch)
+	case ans.revision != "":
+		pp.Constraint = gps.Revision(ans.revision)
+	case ans.version != "":
+		c, err := gps.NewSemverConstraint(ans.version)
+		if err != nil {
+			return pp, errors.Wrap(err, "invalid version constraint")
+		}
+		pp.Constraint = c
+	}
+
+	return pp, nil
+}
@@ -0,0 +1,66 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestRunWizard(t *testing.T) {
+	roots := []string{"github.com/foo/bar", "github.com/baz/qux"}
+	defaults := map[string]string{
+		"github.com/foo/bar": ">=1.0.0, <2.0.0",
+	}
+
+	// Each root is asked source, branch, revision, version, then ignored,
+	// in that order.
+	answers := strings.Join([]string{
+		"", "", "", "", "n", // github.com/foo/bar: no source, accept default version, don't ignore
+		"", "", "", "", "y", // github.com/baz/qux: no source, no constraint, ignore
+	}, "\n") + "\n"
+
+	var out bytes.Buffer
+	m, err := runWizard(&out, strings.NewReader(answers), roots, func(root string) string {
+		return defaults[root]
+	})
+	if err != nil {
+		t.Fatalf("runWizard returned an error: %s", err)
+	}
+
+	got, err := m.MarshalTOML()
+	if err != nil {
+		t.Fatalf("MarshalTOML returned an error: %s", err)
+	}
+
+	want, err := ioutil.ReadFile("testdata/wizard/golden.toml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(got) != string(want) {
+		t.Errorf("wizard output did not marshal as expected:\n\t(GOT): %s\n\t(WNT): %s", got, want)
+	}
+}
+
+func TestRunWizardMultipleConstraints(t *testing.T) {
+	roots := []string{"github.com/foo/bar"}
+
+	// source, branch, revision, version, ignored: both branch and version
+	// answered is invalid.
+	answers := strings.Join([]string{
+		"", "master", "", ">=1.0.0, <2.0.0", "n",
+	}, "\n") + "\n"
+
+	_, err := runWizard(&bytes.Buffer{}, strings.NewReader(answers), roots, func(string) string { return "" })
+	if err == nil {
+		t.Fatal("runWizard with both branch and version answered should have returned an error, but did not")
+	}
+	if !strings.Contains(err.Error(), "multiple constraints") {
+		t.Errorf("unexpected error %q; expected a multiple constraints error", err)
+	}
+}
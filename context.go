@@ -0,0 +1,54 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dep
+
+import "github.com/pkg/errors"
+
+// Ctx carries the ambient state dep's commands share: the project root,
+// plus the solver and VCS lookups that the full dep tool layers on top of
+// Manifest/Lock. This snapshot doesn't vendor that solver or VCS layer, so
+// Ctx's methods are wired up for cmd/dep to call but report that they
+// aren't implemented here rather than silently doing nothing; LatestTag,
+// which has no error result, returns "" instead.
+type Ctx struct {
+	// AbsRoot is the absolute path to the project root dep commands
+	// operate on.
+	AbsRoot string
+}
+
+// Project pairs a project root with the Manifest/Lock dep loaded or solved
+// for it.
+type Project struct {
+	AbsRoot  string
+	Manifest *Manifest
+	Lock     *Lock
+}
+
+// LoadProject reads the Manifest and Lock at ctx.AbsRoot into a Project.
+func (ctx *Ctx) LoadProject() (*Project, error) {
+	return nil, errors.New("LoadProject is not implemented in this snapshot")
+}
+
+// Solve resolves p's Dependencies into a Lock.
+func (ctx *Ctx) Solve(p *Project) (*Lock, error) {
+	return nil, errors.New("Solve is not implemented in this snapshot")
+}
+
+// WriteLock writes solution to p's Gopkg.lock.
+func (ctx *Ctx) WriteLock(p *Project, solution *Lock) error {
+	return errors.New("WriteLock is not implemented in this snapshot")
+}
+
+// DiscoverImportRoots walks root's source tree and returns the import paths
+// it depends on outside the standard library.
+func (ctx *Ctx) DiscoverImportRoots(root string) ([]string, error) {
+	return nil, errors.New("DiscoverImportRoots is not implemented in this snapshot")
+}
+
+// LatestTag stands in for a lookup of the latest semver-ish tag of root's
+// remote repository. It performs no lookup and always returns "".
+func (ctx *Ctx) LatestTag(root string) string {
+	return ""
+}
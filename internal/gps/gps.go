@@ -0,0 +1,142 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package gps contains the core types used to express and solve dependency
+// constraints: project roots, versions, and the rules that relate them.
+package gps
+
+import (
+	"fmt"
+
+	"github.com/Masterminds/semver"
+)
+
+// ProjectRoot is the import path that corresponds to the root directory of
+// a project, as opposed to a particular package within it.
+type ProjectRoot string
+
+// ProjectProperties comprise the properties that can be attached to a
+// ProjectRoot: the constraint placed on its version, and an optional
+// alternate source from which it should be retrieved.
+type ProjectProperties struct {
+	Source     string
+	Constraint Constraint
+}
+
+// VersionType indicates the flavor of a Version: a semver tag, a branch
+// name, or a raw revision.
+type VersionType uint8
+
+const (
+	// IsRevision indicates a Version is a raw VCS revision.
+	IsRevision VersionType = iota
+	// IsBranch indicates a Version is a named VCS branch.
+	IsBranch
+	// IsSemver indicates a Version is a semantic version.
+	IsSemver
+)
+
+// Version is a single identifier for some fixed state of a project: a
+// revision, a branch, or a semver tag. Its method set is a superset of
+// Constraint's, so any Version (e.g. a Revision or the Version NewBranch
+// returns) can be used directly wherever a Constraint is expected - a
+// revision or branch "constrains" a project to exactly itself.
+type Version interface {
+	Type() VersionType
+	String() string
+	Matches(Version) bool
+}
+
+// Revision is an opaque VCS revision, such as a git commit hash.
+type Revision string
+
+// Type implements Version.
+func (r Revision) Type() VersionType { return IsRevision }
+
+// String implements Version.
+func (r Revision) String() string { return string(r) }
+
+// Matches implements Constraint: a Revision only matches itself.
+func (r Revision) Matches(v Version) bool {
+	rev, ok := v.(Revision)
+	return ok && rev == r
+}
+
+type branchVersion struct {
+	name string
+}
+
+// NewBranch creates a Version representing a named VCS branch.
+func NewBranch(name string) Version {
+	return branchVersion{name: name}
+}
+
+func (b branchVersion) Type() VersionType { return IsBranch }
+func (b branchVersion) String() string    { return b.name }
+
+// Matches implements Constraint: a branch only matches the same branch name.
+func (b branchVersion) Matches(v Version) bool {
+	other, ok := v.(branchVersion)
+	return ok && other.name == b.name
+}
+
+type semVersion struct {
+	tag string
+}
+
+// NewVersion creates a Version representing a semantic version tag, e.g.
+// "v0.12.1".
+func NewVersion(tag string) Version {
+	return semVersion{tag: tag}
+}
+
+func (v semVersion) Type() VersionType { return IsSemver }
+func (v semVersion) String() string    { return v.tag }
+
+// Matches implements Constraint: a semver tag only matches the same tag.
+func (v semVersion) Matches(other Version) bool {
+	o, ok := other.(semVersion)
+	return ok && o.tag == v.tag
+}
+
+// Constraint expresses a restriction on which Versions of a project are
+// acceptable.
+type Constraint interface {
+	Matches(Version) bool
+	String() string
+}
+
+// semverConstraint holds only the constraint string, not the parsed
+// semver.Constraints: that type embeds a func field, which reflect.DeepEqual
+// never considers equal across separately-parsed instances, and
+// ProjectProperties (which embeds a Constraint) is compared with DeepEqual
+// elsewhere in the codebase. Re-parsing in Matches keeps two
+// semverConstraints built from the same body DeepEqual-comparable.
+type semverConstraint struct {
+	body string
+}
+
+func (c semverConstraint) Matches(v Version) bool {
+	sv, err := semver.NewVersion(v.String())
+	if err != nil {
+		return false
+	}
+	cs, err := semver.NewConstraint(c.body)
+	if err != nil {
+		return false
+	}
+	ok, _ := cs.Validate(sv)
+	return ok
+}
+
+func (c semverConstraint) String() string { return c.body }
+
+// NewSemverConstraint parses body as a Masterminds/semver constraint string
+// (e.g. ">=0.12.0, <1.0.0") and returns the resulting Constraint.
+func NewSemverConstraint(body string) (Constraint, error) {
+	if _, err := semver.NewConstraint(body); err != nil {
+		return nil, fmt.Errorf("could not parse %q as a semver constraint: %s", body, err)
+	}
+	return semverConstraint{body: body}, nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gps
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// goToolchainVersion matches a Go toolchain-style version tag: go1,
+// go1.N, go1.N.M, and their beta/rc prereleases (go1.13beta1, go1.9rc2).
+var goToolchainVersion = regexp.MustCompile(`^go(\d+)(?:\.(\d+)(?:\.(\d+))?)?(beta|rc)?(\d*)$`)
+
+// strictSemver matches a bare or "v"-prefixed MAJOR.MINOR.PATCH, optionally
+// followed by a prerelease and/or build metadata.
+var strictSemver = regexp.MustCompile(`^v?\d+\.\d+\.\d+(-[0-9A-Za-z.-]+)?(\+[0-9A-Za-z.-]+)?$`)
+
+// normalizeToolchainVersion canonicalizes a single version token for use in
+// a semver constraint: go1 -> v1.0.0, go1.N -> v1.N.0, go1.N.M -> v1.N.M,
+// and go1.Nbeta/rc[K] -> v1.N.0-beta.K/v1.N.0-rc.K. Tokens that aren't
+// go-toolchain style are required to already be valid semver; anything else
+// (e.g. "v1.x" or "v1.0-") is rejected.
+func normalizeToolchainVersion(token string) (string, error) {
+	if m := goToolchainVersion.FindStringSubmatch(token); m != nil {
+		major, minor, patch, pre, preNum := m[1], m[2], m[3], m[4], m[5]
+		if minor == "" {
+			minor = "0"
+		}
+		if patch == "" {
+			patch = "0"
+		}
+
+		v := fmt.Sprintf("v%s.%s.%s", major, minor, patch)
+		if pre != "" {
+			if preNum == "" {
+				preNum = "0"
+			}
+			v += fmt.Sprintf("-%s.%s", pre, preNum)
+		}
+		return v, nil
+	}
+
+	if !strictSemver.MatchString(token) {
+		return "", fmt.Errorf("malformed version %q", token)
+	}
+	if !strings.HasPrefix(token, "v") {
+		token = "v" + token
+	}
+	return token, nil
+}
+
+// constraintClause splits a single comma-separated constraint clause into
+// its comparison operator (if any) and version token.
+var constraintClause = regexp.MustCompile(`^(>=|<=|==|!=|>|<|=|\^|~)?\s*(.+)$`)
+
+// NewToolchainConstraint parses body as a Masterminds/semver constraint
+// string whose version tokens may be Go toolchain-style tags (go1,
+// go1.13beta1, go1.9rc2, ...) in addition to plain semver, normalizing each
+// token to canonical semver before handing the whole constraint to
+// NewSemverConstraint.
+func NewToolchainConstraint(body string) (Constraint, error) {
+	clauses := strings.Split(body, ",")
+	normalized := make([]string, len(clauses))
+
+	for i, clause := range clauses {
+		clause = strings.TrimSpace(clause)
+		m := constraintClause.FindStringSubmatch(clause)
+		if m == nil {
+			return nil, fmt.Errorf("malformed constraint clause: %q", clause)
+		}
+
+		op, token := m[1], m[2]
+		norm, err := normalizeToolchainVersion(token)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %s", body, err)
+		}
+		normalized[i] = op + norm
+	}
+
+	return NewSemverConstraint(strings.Join(normalized, ", "))
+}
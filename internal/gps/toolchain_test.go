@@ -0,0 +1,55 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gps
+
+import "testing"
+
+func TestNormalizeToolchainVersion(t *testing.T) {
+	cases := []struct {
+		in, want string
+	}{
+		{"go1", "v1.0.0"},
+		{"go1.9", "v1.9.0"},
+		{"go1.9.3", "v1.9.3"},
+		{"go1.13beta1", "v1.13.0-beta.1"},
+		{"go1.9rc2", "v1.9.0-rc.2"},
+		{"1.2.3", "v1.2.3"},
+		{"v1.2.3", "v1.2.3"},
+	}
+
+	for _, c := range cases {
+		got, err := normalizeToolchainVersion(c.in)
+		if err != nil {
+			t.Errorf("normalizeToolchainVersion(%q) returned an unexpected error: %s", c.in, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("normalizeToolchainVersion(%q) = %q, want %q", c.in, got, c.want)
+		}
+	}
+}
+
+func TestNormalizeToolchainVersionRejectsMalformed(t *testing.T) {
+	for _, in := range []string{"v1.x", "v1.0-", "go1.x", ""} {
+		if _, err := normalizeToolchainVersion(in); err == nil {
+			t.Errorf("normalizeToolchainVersion(%q) should have returned an error, but did not", in)
+		}
+	}
+}
+
+func TestNewToolchainConstraint(t *testing.T) {
+	c, err := NewToolchainConstraint(">=go1.12.0, <go1.14")
+	if err != nil {
+		t.Fatalf("NewToolchainConstraint returned an unexpected error: %s", err)
+	}
+
+	if c.String() == "" {
+		t.Error("expected a non-empty constraint string")
+	}
+
+	if _, err := NewToolchainConstraint(">=go1.x"); err == nil {
+		t.Error("NewToolchainConstraint with a malformed version should have returned an error, but did not")
+	}
+}
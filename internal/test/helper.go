@@ -0,0 +1,55 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package test holds small helpers shared by tests across the dep tree,
+// chiefly for loading and updating golden files under testdata.
+package test
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// UpdateGolden causes TestXxx functions that compare against golden files to
+// rewrite those files with the actual output instead of failing.
+var UpdateGolden = flag.Bool("update", false, "update golden files")
+
+// Helper bundles the state needed to read and write testdata fixtures.
+type Helper struct {
+	t *testing.T
+}
+
+// NewHelper returns a Helper bound to t.
+func NewHelper(t *testing.T) *Helper {
+	return &Helper{t: t}
+}
+
+// Cleanup releases any resources held by the Helper.
+func (h *Helper) Cleanup() {}
+
+// GetTestFile opens the named file under testdata for reading.
+func (h *Helper) GetTestFile(name string) *os.File {
+	f, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		h.t.Fatal(err)
+	}
+	return f
+}
+
+// GetTestFileString returns the contents of the named file under testdata.
+func (h *Helper) GetTestFileString(name string) string {
+	b, err := ioutil.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		h.t.Fatal(err)
+	}
+	return string(b)
+}
+
+// WriteTestFile overwrites the named file under testdata with contents.
+func (h *Helper) WriteTestFile(name, contents string) error {
+	return ioutil.WriteFile(filepath.Join("testdata", name), []byte(contents), 0644)
+}
@@ -0,0 +1,33 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dep
+
+import "github.com/golang/dep/internal/gps"
+
+// LockName is the lock file name used by dep.
+const LockName = "Gopkg.lock"
+
+// LockedProject is a single project entry in a Lock: the source it was
+// fetched from, the revision dep solved it to, and - if the constraint that
+// produced it was a branch or semver tag rather than a bare revision - the
+// Version dep resolved that constraint to.
+type LockedProject struct {
+	Root     gps.ProjectRoot
+	Source   string
+	Version  gps.Version
+	Revision gps.Revision
+
+	// Digest is the content hash dep recorded for this revision, if any,
+	// in the same form as a go.sum "h1:" entry. It is empty when dep has
+	// not computed one.
+	Digest string
+}
+
+// Lock is dep's lock file: the exact, reproducible set of dependency
+// revisions that satisfied the Manifest the last time `dep ensure` solved
+// the project.
+type Lock struct {
+	Projects []LockedProject
+}
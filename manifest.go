@@ -0,0 +1,311 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dep
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/BurntSushi/toml"
+	"github.com/golang/dep/internal/gps"
+	"github.com/pkg/errors"
+)
+
+// ManifestName is the manifest file name used by dep.
+const ManifestName = "Gopkg.toml"
+
+// Manifest is dep's manifest: the set of versioning constraints the user has
+// expressed for the dependencies of their project, plus source overrides and
+// packages to ignore.
+type Manifest struct {
+	Dependencies map[gps.ProjectRoot]gps.ProjectProperties
+	Ovr          map[gps.ProjectRoot]gps.ProjectProperties
+	Ignored      []string
+
+	// Toolchain holds the constraints from the manifest's [toolchain]
+	// table, keyed by name (conventionally "go"). Its constraint strings
+	// may use Go toolchain-style version tags; see gps.NewToolchainConstraint.
+	Toolchain map[string]gps.Constraint
+}
+
+// rawManifest is the TOML-serializable form of a Manifest.
+type rawManifest struct {
+	Dependencies []rawProject           `toml:"dependencies,omitempty"`
+	Overrides    []rawProject           `toml:"overrides,omitempty"`
+	Ignored      []string               `toml:"ignored,omitempty"`
+	Variables    map[string]string      `toml:"variables,omitempty"`
+	Toolchain    map[string]string      `toml:"toolchain,omitempty"`
+	Metadata     map[string]interface{} `toml:"metadata,omitempty"`
+}
+
+type rawProject struct {
+	Name     string                 `toml:"name"`
+	Branch   string                 `toml:"branch,omitempty"`
+	Revision string                 `toml:"revision,omitempty"`
+	Version  string                 `toml:"version,omitempty"`
+	Source   string                 `toml:"source,omitempty"`
+	Metadata map[string]interface{} `toml:"metadata,omitempty"`
+}
+
+// readManifest parses a Gopkg.toml from r, returning the resulting
+// Manifest, a slice of non-fatal warnings, and an error if the manifest
+// could not be read at all.
+func readManifest(r io.Reader) (*Manifest, []error, error) {
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(r); err != nil {
+		return nil, nil, errors.Wrap(err, "error reading manifest")
+	}
+
+	var raw rawManifest
+	if err := toml.Unmarshal(buf.Bytes(), &raw); err != nil {
+		return nil, nil, errors.Wrap(err, "unable to parse the manifest as TOML")
+	}
+
+	if err := expandManifestVars(&raw); err != nil {
+		return nil, nil, errors.Wrap(err, "error expanding variables")
+	}
+
+	m := &Manifest{
+		Dependencies: make(map[gps.ProjectRoot]gps.ProjectProperties, len(raw.Dependencies)),
+		Ovr:          make(map[gps.ProjectRoot]gps.ProjectProperties, len(raw.Overrides)),
+		Ignored:      raw.Ignored,
+		Toolchain:    make(map[string]gps.Constraint, len(raw.Toolchain)),
+	}
+
+	for name, body := range raw.Toolchain {
+		c, err := gps.NewToolchainConstraint(body)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "toolchain.%s", name)
+		}
+		m.Toolchain[name] = c
+	}
+
+	seen := make(map[string]bool, len(raw.Dependencies))
+	for _, rp := range raw.Dependencies {
+		if seen[rp.Name] {
+			return nil, nil, fmt.Errorf("%s: multiple dependencies specified for the same project root", rp.Name)
+		}
+		seen[rp.Name] = true
+
+		pp, err := toProjectProperties(rp)
+		if err != nil {
+			return nil, nil, err
+		}
+		m.Dependencies[gps.ProjectRoot(rp.Name)] = pp
+	}
+
+	for _, rp := range raw.Overrides {
+		pp, err := toProjectProperties(rp)
+		if err != nil {
+			return nil, nil, err
+		}
+		m.Ovr[gps.ProjectRoot(rp.Name)] = pp
+	}
+
+	return m, nil, nil
+}
+
+// toProjectProperties converts a single raw TOML project entry into a
+// gps.ProjectProperties, rejecting entries that specify more than one kind
+// of version constraint.
+func toProjectProperties(rp rawProject) (gps.ProjectProperties, error) {
+	pp := gps.ProjectProperties{Source: rp.Source}
+
+	set := 0
+	if rp.Branch != "" {
+		set++
+	}
+	if rp.Revision != "" {
+		set++
+	}
+	if rp.Version != "" {
+		set++
+	}
+	if set > 1 {
+		return pp, fmt.Errorf("%s: multiple constraints (branch, revision, or version) specified", rp.Name)
+	}
+
+	switch {
+	case rp.Branch != "":
+		pp.Constraint = gps.NewBranch(rp.Branch)
+	case rp.Revision != "":
+		pp.Constraint = gps.Revision(rp.Revision)
+	case rp.Version != "":
+		c, err := gps.NewSemverConstraint(rp.Version)
+		if err != nil {
+			return pp, errors.Wrapf(err, "%s: invalid version constraint", rp.Name)
+		}
+		pp.Constraint = c
+	}
+
+	return pp, nil
+}
+
+// MarshalTOML serializes m into the canonical Gopkg.toml form, with
+// dependencies and overrides sorted by project root for stable output.
+func (m *Manifest) MarshalTOML() ([]byte, error) {
+	raw := rawManifest{
+		Dependencies: fromProjectProperties(m.Dependencies),
+		Overrides:    fromProjectProperties(m.Ovr),
+		Ignored:      m.Ignored,
+	}
+	if len(m.Toolchain) > 0 {
+		raw.Toolchain = make(map[string]string, len(m.Toolchain))
+		for name, c := range m.Toolchain {
+			raw.Toolchain[name] = c.String()
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(raw); err != nil {
+		return nil, errors.Wrap(err, "unable to marshal the manifest to TOML")
+	}
+	return buf.Bytes(), nil
+}
+
+func fromProjectProperties(m map[gps.ProjectRoot]gps.ProjectProperties) []rawProject {
+	roots := make([]string, 0, len(m))
+	for root := range m {
+		roots = append(roots, string(root))
+	}
+	sort.Strings(roots)
+
+	raw := make([]rawProject, 0, len(roots))
+	for _, root := range roots {
+		pp := m[gps.ProjectRoot(root)]
+		rp := rawProject{Name: root, Source: pp.Source}
+		switch c := pp.Constraint.(type) {
+		case gps.Revision:
+			rp.Revision = c.String()
+		case gps.Version:
+			if c.Type() == gps.IsBranch {
+				rp.Branch = c.String()
+			}
+		default:
+			if pp.Constraint != nil {
+				rp.Version = pp.Constraint.String()
+			}
+		}
+		raw = append(raw, rp)
+	}
+	return raw
+}
+
+// ManifestFields and ProjectFields list the TOML keys validateManifest
+// accepts at the top level and within a single [[dependencies]]/[[overrides]]
+// entry, respectively. They're exported so other packages (e.g. cmd/dep's
+// init wizard) can drive their own behavior from the same schema rather than
+// hand-maintaining a second list that could drift from it.
+var ManifestFields = map[string]bool{
+	"dependencies": true,
+	"overrides":    true,
+	"ignored":      true,
+	"variables":    true,
+	"toolchain":    true,
+	"metadata":     true,
+}
+
+var ProjectFields = map[string]bool{
+	"name":     true,
+	"branch":   true,
+	"revision": true,
+	"version":  true,
+	"source":   true,
+	"metadata": true,
+}
+
+// validateManifest parses tomlString as a generic TOML document and reports
+// any fields or structures that readManifest would silently ignore.
+func validateManifest(tomlString string) ([]error, error) {
+	var raw map[string]interface{}
+	if _, err := toml.Decode(tomlString, &raw); err != nil {
+		return nil, errors.Wrap(err, "unable to parse the manifest as TOML")
+	}
+
+	var errs []error
+	for field, val := range raw {
+		if !ManifestFields[field] {
+			errs = append(errs, fmt.Errorf("Unknown field in manifest: %s", field))
+			continue
+		}
+
+		switch field {
+		case "metadata":
+			if _, ok := val.(map[string]interface{}); !ok {
+				errs = append(errs, fmt.Errorf("metadata should be a TOML table"))
+			}
+		case "dependencies", "overrides":
+			errs = append(errs, validateProjectsField(field, val)...)
+		case "variables":
+			errs = append(errs, validateVariablesField(val)...)
+		case "toolchain":
+			errs = append(errs, validateToolchainField(val)...)
+		}
+	}
+
+	errs = append(errs, validateVariableRefs(raw)...)
+
+	return errs, nil
+}
+
+func validateVariablesField(val interface{}) []error {
+	tbl, ok := val.(map[string]interface{})
+	if !ok {
+		return []error{errors.New("variables should be a TOML table")}
+	}
+
+	var errs []error
+	for name, v := range tbl {
+		if _, ok := v.(string); !ok {
+			errs = append(errs, fmt.Errorf("variables.%s should be a string", name))
+		}
+	}
+	return errs
+}
+
+func validateToolchainField(val interface{}) []error {
+	tbl, ok := val.(map[string]interface{})
+	if !ok {
+		return []error{errors.New("toolchain should be a TOML table")}
+	}
+
+	var errs []error
+	for name, v := range tbl {
+		s, ok := v.(string)
+		if !ok {
+			errs = append(errs, fmt.Errorf("toolchain.%s should be a string", name))
+			continue
+		}
+		if _, err := gps.NewToolchainConstraint(s); err != nil {
+			errs = append(errs, fmt.Errorf("toolchain.%s: %s", name, err))
+		}
+	}
+	return errs
+}
+
+func validateProjectsField(field string, val interface{}) []error {
+	tables, ok := val.([]map[string]interface{})
+	if !ok {
+		return []error{fmt.Errorf("%s should be a TOML array of tables", field)}
+	}
+
+	var errs []error
+	for _, tbl := range tables {
+		for k, v := range tbl {
+			if !ProjectFields[k] {
+				errs = append(errs, fmt.Errorf("Invalid key %q in %q", k, field))
+				continue
+			}
+			if k == "metadata" {
+				if _, ok := v.(map[string]interface{}); !ok {
+					errs = append(errs, fmt.Errorf("metadata in %q should be a TOML table", field))
+				}
+			}
+		}
+	}
+	return errs
+}
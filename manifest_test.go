@@ -6,6 +6,7 @@ package dep
 
 import (
 	"errors"
+	"os"
 	"reflect"
 	"strings"
 	"testing"
@@ -96,6 +97,69 @@ func TestWriteManifest(t *testing.T) {
 	}
 }
 
+func TestReadManifestVariableExpansion(t *testing.T) {
+	os.Setenv("DEP_TEST_ORG", "golang")
+	defer os.Unsetenv("DEP_TEST_ORG")
+
+	tomlString := `
+	[variables]
+	  org = "${DEP_TEST_ORG}"
+	  fork = "${org}/dep"
+
+	[[overrides]]
+	  name = "github.com/golang/dep/internal/gps"
+	  branch = "master"
+	  source = "https://github.com/${fork}/internal/gps"
+	`
+
+	got, _, err := readManifest(strings.NewReader(tomlString))
+	if err != nil {
+		t.Fatalf("Should have read Manifest correctly, but got err %q", err)
+	}
+
+	ovr := got.Ovr[gps.ProjectRoot("github.com/golang/dep/internal/gps")]
+	want := "https://github.com/golang/dep/internal/gps"
+	if ovr.Source != want {
+		t.Errorf("Override source was not expanded as expected:\n\t(GOT): %s\n\t(WNT): %s", ovr.Source, want)
+	}
+}
+
+func TestReadManifestVariableCycle(t *testing.T) {
+	tomlString := `
+	[variables]
+	  a = "${b}"
+	  b = "${a}"
+	`
+
+	_, _, err := readManifest(strings.NewReader(tomlString))
+	if err == nil {
+		t.Fatal("Reading a manifest with a variable cycle should have caused an error, but did not")
+	}
+	if !strings.Contains(err.Error(), "circular variable reference") {
+		t.Errorf("Unexpected error %q; expected a circular variable reference error", err)
+	}
+}
+
+func TestReadManifestToolchain(t *testing.T) {
+	tomlString := `
+	[toolchain]
+	  go = ">=go1.12, <go1.14"
+	`
+
+	got, _, err := readManifest(strings.NewReader(tomlString))
+	if err != nil {
+		t.Fatalf("Should have read Manifest correctly, but got err %q", err)
+	}
+
+	c, ok := got.Toolchain["go"]
+	if !ok {
+		t.Fatal("Expected a \"go\" entry in Manifest.Toolchain")
+	}
+	if want := ">=v1.12.0, <v1.14.0"; c.String() != want {
+		t.Errorf("Toolchain constraint was not normalized as expected:\n\t(GOT): %s\n\t(WNT): %s", c.String(), want)
+	}
+}
+
 func TestReadManifestErrors(t *testing.T) {
 	h := test.NewHelper(t)
 	defer h.Cleanup()
@@ -206,6 +270,35 @@ func TestValidateManifest(t *testing.T) {
 			`,
 			want: []error{},
 		},
+		{
+			tomlString: `
+			[variables]
+			  fork = "${UNDEFINED_DEP_TEST_VAR}/dep"
+			`,
+			want: []error{errors.New("undefined variable reference: UNDEFINED_DEP_TEST_VAR")},
+		},
+		{
+			tomlString: `
+			[variables]
+			  a = "${b}"
+			  b = "${a}"
+			`,
+			want: []error{errors.New("circular variable reference: a -> b -> a")},
+		},
+		{
+			tomlString: `
+			[toolchain]
+			  go = ">=go1.12, <go1.14"
+			`,
+			want: []error{},
+		},
+		{
+			tomlString: `
+			[toolchain]
+			  go = ">=go1.x"
+			`,
+			want: []error{errors.New(`toolchain.go: ">=go1.x": malformed version "go1.x"`)},
+		},
 	}
 
 	// constains for error
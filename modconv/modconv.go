@@ -0,0 +1,252 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package modconv imports a Go modules go.mod/go.sum pair into dep's
+// Manifest/Lock types, so that `dep init --from-gomod` can bootstrap a
+// project that has already migrated to modules, or is being migrated away
+// from them.
+package modconv
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/golang/dep"
+	"github.com/golang/dep/internal/gps"
+)
+
+// ImportGoMod converts gomod (the contents of a go.mod file) and gosum (the
+// contents of the matching go.sum, or nil if there isn't one) into a
+// Manifest and Lock. require directives become Dependencies with semver
+// constraints, replace directives become Ovr entries, and exclude
+// directives are recorded in Ignored.
+func ImportGoMod(gomod io.Reader, gosum io.Reader) (*dep.Manifest, *dep.Lock, error) {
+	mf, err := parseGoMod(gomod)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to parse go.mod: %s", err)
+	}
+
+	digests := map[string]string{}
+	if gosum != nil {
+		var err error
+		digests, err = parseGoSum(gosum)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to parse go.sum: %s", err)
+		}
+	}
+
+	m := &dep.Manifest{
+		Dependencies: make(map[gps.ProjectRoot]gps.ProjectProperties, len(mf.require)),
+		Ovr:          make(map[gps.ProjectRoot]gps.ProjectProperties, len(mf.replace)),
+	}
+	l := &dep.Lock{}
+
+	for _, req := range mf.require {
+		root := gps.ProjectRoot(req.path)
+		m.Dependencies[root] = gps.ProjectProperties{Constraint: versionConstraint(req.version)}
+
+		lp := dep.LockedProject{
+			Root:   root,
+			Digest: digests[req.path+"@"+req.version],
+		}
+		// LockedProject.Version expects a gps.Version (a single resolved
+		// version), not the gps.Constraint req.version parses to. A
+		// pseudo-version's embedded commit hash recovers a Revision; a
+		// tagged version has no commit hash available here, so it locks to
+		// the tag itself instead.
+		if rev := pseudoVersionRevision(req.version); rev != "" {
+			lp.Revision = rev
+		} else {
+			lp.Version = gps.NewVersion(req.version)
+		}
+		l.Projects = append(l.Projects, lp)
+	}
+
+	for _, rep := range mf.replace {
+		root := gps.ProjectRoot(rep.oldPath)
+		pp := gps.ProjectProperties{}
+		if rep.newPath != rep.oldPath {
+			pp.Source = rep.newPath
+		}
+		if rep.newVersion != "" {
+			pp.Constraint = versionConstraint(rep.newVersion)
+		}
+		m.Ovr[root] = pp
+	}
+
+	for _, exc := range mf.exclude {
+		m.Ignored = append(m.Ignored, exc.path)
+	}
+
+	return m, l, nil
+}
+
+// versionConstraint converts a go.mod version string into a gps.Constraint:
+// pseudo-versions resolve to the embedded revision, everything else is
+// treated as a semver constraint pinned to that exact version, and a version
+// that does not parse as one becomes a Revision of the raw string.
+func versionConstraint(version string) gps.Constraint {
+	if rev := pseudoVersionRevision(version); rev != "" {
+		return rev
+	}
+	c, err := gps.NewSemverConstraint(version)
+	if err != nil {
+		return gps.Revision(version)
+	}
+	return c
+}
+
+var pseudoVersionPattern = regexp.MustCompile(`-([0-9a-f]{12})$`)
+
+// pseudoVersionRevision extracts the commit hash embedded in a Go
+// pseudo-version such as v0.0.0-20190101000000-abcdef123456, returning "" if
+// version isn't a pseudo-version.
+func pseudoVersionRevision(version string) gps.Revision {
+	m := pseudoVersionPattern.FindStringSubmatch(version)
+	if m == nil {
+		return ""
+	}
+	return gps.Revision(m[1])
+}
+
+type requirement struct {
+	path, version string
+}
+
+type replacement struct {
+	oldPath, oldVersion string
+	newPath, newVersion string
+}
+
+type exclusion struct {
+	path, version string
+}
+
+type goMod struct {
+	module  string
+	require []requirement
+	replace []replacement
+	exclude []exclusion
+}
+
+// parseGoMod is a small, purpose-built parser for the subset of go.mod
+// syntax ImportGoMod needs: module, require, replace, and exclude
+// directives, each either as a single line or a "( ... )" block.
+func parseGoMod(r io.Reader) (*goMod, error) {
+	mf := &goMod{}
+	scanner := bufio.NewScanner(r)
+
+	var block string
+	for scanner.Scan() {
+		line := stripComment(scanner.Text())
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		if block != "" {
+			if line == ")" {
+				block = ""
+				continue
+			}
+			if err := mf.addDirective(block, line); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		directive := fields[0]
+		rest := strings.TrimSpace(strings.TrimPrefix(line, directive))
+
+		if rest == "(" {
+			block = directive
+			continue
+		}
+
+		if directive == "module" {
+			mf.module = rest
+			continue
+		}
+
+		if err := mf.addDirective(directive, rest); err != nil {
+			return nil, err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return mf, nil
+}
+
+func (mf *goMod) addDirective(directive, rest string) error {
+	fields := strings.Fields(rest)
+	switch directive {
+	case "require":
+		if len(fields) < 2 {
+			return fmt.Errorf("malformed require directive: %q", rest)
+		}
+		mf.require = append(mf.require, requirement{path: fields[0], version: fields[1]})
+	case "exclude":
+		if len(fields) < 2 {
+			return fmt.Errorf("malformed exclude directive: %q", rest)
+		}
+		mf.exclude = append(mf.exclude, exclusion{path: fields[0], version: fields[1]})
+	case "replace":
+		arrow := -1
+		for i, f := range fields {
+			if f == "=>" {
+				arrow = i
+				break
+			}
+		}
+		if arrow < 0 {
+			return fmt.Errorf("malformed replace directive: %q", rest)
+		}
+		oldSide, newSide := fields[:arrow], fields[arrow+1:]
+		rep := replacement{oldPath: oldSide[0]}
+		if len(oldSide) > 1 {
+			rep.oldVersion = oldSide[1]
+		}
+		rep.newPath = newSide[0]
+		if len(newSide) > 1 {
+			rep.newVersion = newSide[1]
+		}
+		mf.replace = append(mf.replace, rep)
+	}
+	return nil
+}
+
+func stripComment(line string) string {
+	if i := strings.Index(line, "//"); i >= 0 {
+		return line[:i]
+	}
+	return line
+}
+
+// parseGoSum reads a go.sum file into a path@version -> "h1:..." digest map,
+// skipping the /go.mod hash lines dep has no use for.
+func parseGoSum(r io.Reader) (map[string]string, error) {
+	digests := map[string]string{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 {
+			continue
+		}
+		path, version, sum := fields[0], fields[1], fields[2]
+		if strings.HasSuffix(version, "/go.mod") {
+			continue
+		}
+		digests[path+"@"+version] = sum
+	}
+	return digests, scanner.Err()
+}
@@ -0,0 +1,77 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package modconv
+
+import (
+	"os"
+	"testing"
+
+	"github.com/golang/dep/internal/gps"
+)
+
+func TestImportGoMod(t *testing.T) {
+	gomod, err := os.Open("../testdata/modconv/go.mod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gomod.Close()
+
+	gosum, err := os.Open("../testdata/modconv/go.sum")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gosum.Close()
+
+	m, l, err := ImportGoMod(gomod, gosum)
+	if err != nil {
+		t.Fatalf("ImportGoMod returned an error: %s", err)
+	}
+
+	brook := m.Dependencies[gps.ProjectRoot("github.com/babble/brook")]
+	if want := gps.Revision("d05d5aca9f89"); brook.Constraint != want {
+		t.Errorf("pseudo-version was not decoded to a revision constraint:\n\t(GOT): %#v\n\t(WNT): %#v", brook.Constraint, want)
+	}
+
+	gpsProps := m.Ovr[gps.ProjectRoot("github.com/golang/dep/internal/gps")]
+	if want := "github.com/example/gps"; gpsProps.Source != want {
+		t.Errorf("replace directive did not produce an override source:\n\t(GOT): %s\n\t(WNT): %s", gpsProps.Source, want)
+	}
+
+	if want := []string{"github.com/foo/bar"}; len(m.Ignored) != 1 || m.Ignored[0] != want[0] {
+		t.Errorf("exclude directive did not populate Ignored:\n\t(GOT): %v\n\t(WNT): %v", m.Ignored, want)
+	}
+
+	var found bool
+	for _, lp := range l.Projects {
+		if lp.Root == gps.ProjectRoot("github.com/babble/brook") {
+			found = true
+			if lp.Revision != gps.Revision("d05d5aca9f89") {
+				t.Errorf("locked revision did not match the pseudo-version commit:\n\t(GOT): %s\n\t(WNT): d05d5aca9f89", lp.Revision)
+			}
+			if lp.Digest == "" {
+				t.Error("expected a digest from go.sum to be carried into the Lock")
+			}
+		}
+	}
+	if !found {
+		t.Error("expected github.com/babble/brook to be locked")
+	}
+
+	found = false
+	for _, lp := range l.Projects {
+		if lp.Root == gps.ProjectRoot("github.com/golang/dep/internal/gps") {
+			found = true
+			if lp.Revision != "" {
+				t.Errorf("tagged require should not have locked a revision, got: %s", lp.Revision)
+			}
+			if want := gps.NewVersion("v0.12.1"); lp.Version != want {
+				t.Errorf("tagged require did not lock to its version:\n\t(GOT): %#v\n\t(WNT): %#v", lp.Version, want)
+			}
+		}
+	}
+	if !found {
+		t.Error("expected github.com/golang/dep/internal/gps to be locked")
+	}
+}
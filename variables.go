@@ -0,0 +1,221 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dep
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+)
+
+// varRefPattern matches both ${NAME} and bare $NAME variable references.
+var varRefPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}|\$([A-Za-z_][A-Za-z0-9_]*)`)
+
+// varRefs returns the names referenced by s, in the order they appear.
+func varRefs(s string) []string {
+	var refs []string
+	for _, m := range varRefPattern.FindAllStringSubmatch(s, -1) {
+		if m[1] != "" {
+			refs = append(refs, m[1])
+		} else {
+			refs = append(refs, m[2])
+		}
+	}
+	return refs
+}
+
+// expandVars resolves every entry in vars against itself (allowing
+// cross-references, e.g. `default = "${ORG}/fork"`) and the process
+// environment, returning the fully-resolved table. A reference to a name
+// that isn't in vars falls back to os.Getenv, which yields "" if unset.
+func expandVars(vars map[string]string) (map[string]string, error) {
+	resolved := make(map[string]string, len(vars))
+	visiting := make(map[string]bool, len(vars))
+
+	var resolve func(name string, stack []string) (string, error)
+	resolve = func(name string, stack []string) (string, error) {
+		if v, ok := resolved[name]; ok {
+			return v, nil
+		}
+		raw, ok := vars[name]
+		if !ok {
+			return os.Getenv(name), nil
+		}
+		if visiting[name] {
+			return "", fmt.Errorf("circular variable reference: %s -> %s", joinCycle(stack), name)
+		}
+		visiting[name] = true
+		defer delete(visiting, name)
+
+		v, err := expandRefs(raw, func(ref string) (string, error) {
+			return resolve(ref, append(stack, name))
+		})
+		if err != nil {
+			return "", err
+		}
+		resolved[name] = v
+		return v, nil
+	}
+
+	// Resolve in a stable order so that unrelated errors (e.g. an
+	// unrelated cycle) are always reported in the same order.
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if _, err := resolve(name, nil); err != nil {
+			return nil, err
+		}
+	}
+	return resolved, nil
+}
+
+func joinCycle(stack []string) string {
+	s := ""
+	for i, name := range stack {
+		if i > 0 {
+			s += " -> "
+		}
+		s += name
+	}
+	return s
+}
+
+// expandRefs replaces every ${NAME}/$NAME reference in s using lookup.
+func expandRefs(s string, lookup func(name string) (string, error)) (string, error) {
+	var outerErr error
+	out := varRefPattern.ReplaceAllStringFunc(s, func(match string) string {
+		sub := varRefPattern.FindStringSubmatch(match)
+		name := sub[1]
+		if name == "" {
+			name = sub[2]
+		}
+		v, err := lookup(name)
+		if err != nil && outerErr == nil {
+			outerErr = err
+		}
+		return v
+	})
+	if outerErr != nil {
+		return "", outerErr
+	}
+	return out, nil
+}
+
+// validateVariableRefs checks the [variables] table for cycles and checks
+// the variables table itself, the source/branch/version/revision fields of
+// [[dependencies]] and [[overrides]], and ignored for references to names
+// that are neither defined in [variables] nor set in the process
+// environment.
+func validateVariableRefs(raw map[string]interface{}) []error {
+	vars := map[string]string{}
+	if tbl, ok := raw["variables"].(map[string]interface{}); ok {
+		for name, v := range tbl {
+			if s, ok := v.(string); ok {
+				vars[name] = s
+			}
+		}
+	}
+
+	var errs []error
+	if _, err := expandVars(vars); err != nil {
+		errs = append(errs, err)
+	}
+
+	checkRefs := func(s string) {
+		for _, ref := range varRefs(s) {
+			if _, ok := vars[ref]; ok {
+				continue
+			}
+			if _, ok := os.LookupEnv(ref); ok {
+				continue
+			}
+			errs = append(errs, fmt.Errorf("undefined variable reference: %s", ref))
+		}
+	}
+
+	for _, v := range vars {
+		checkRefs(v)
+	}
+	for _, field := range []string{"dependencies", "overrides"} {
+		tables, _ := raw[field].([]map[string]interface{})
+		for _, tbl := range tables {
+			for _, key := range []string{"source", "branch", "version", "revision"} {
+				if s, ok := tbl[key].(string); ok {
+					checkRefs(s)
+				}
+			}
+		}
+	}
+	if ignored, ok := raw["ignored"].([]interface{}); ok {
+		for _, v := range ignored {
+			if s, ok := v.(string); ok {
+				checkRefs(s)
+			}
+		}
+	}
+
+	return errs
+}
+
+// expandManifestVars expands every ${VAR}/$VAR reference in raw's
+// source/branch/version/revision and ignored fields, resolving names first
+// against raw.Variables (the manifest's own [variables] table) and then against the
+// process environment.
+func expandManifestVars(raw *rawManifest) error {
+	resolved, err := expandVars(raw.Variables)
+	if err != nil {
+		return err
+	}
+
+	lookup := func(name string) (string, error) {
+		if v, ok := resolved[name]; ok {
+			return v, nil
+		}
+		return os.Getenv(name), nil
+	}
+
+	expand := func(s string) (string, error) { return expandRefs(s, lookup) }
+
+	expandProjects := func(projects []rawProject) error {
+		for i := range projects {
+			var err error
+			if projects[i].Source, err = expand(projects[i].Source); err != nil {
+				return err
+			}
+			if projects[i].Branch, err = expand(projects[i].Branch); err != nil {
+				return err
+			}
+			if projects[i].Version, err = expand(projects[i].Version); err != nil {
+				return err
+			}
+			if projects[i].Revision, err = expand(projects[i].Revision); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := expandProjects(raw.Dependencies); err != nil {
+		return err
+	}
+	if err := expandProjects(raw.Overrides); err != nil {
+		return err
+	}
+
+	for i, ig := range raw.Ignored {
+		v, err := expand(ig)
+		if err != nil {
+			return err
+		}
+		raw.Ignored[i] = v
+	}
+
+	return nil
+}